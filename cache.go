@@ -0,0 +1,81 @@
+package whois
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// defaultCacheTTL is how long a cached response is considered fresh when
+// Client.SetCache is called with ttl <= 0
+const defaultCacheTTL = 24 * time.Hour
+
+// queryCache is a small in-memory LRU cache of whois responses keyed on
+// lowercased domain, used to avoid re-querying registries that rate-limit
+// or ban frequent lookups
+type queryCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key       string
+	server    string
+	body      string
+	fetchedAt time.Time
+}
+
+func newQueryCache(maxEntries int, ttl time.Duration) *queryCache {
+	return &queryCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (c *queryCache) get(key string) (server, body string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, found := c.items[key]
+	if !found {
+		return "", "", false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if time.Since(entry.fetchedAt) > c.ttl {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return "", "", false
+	}
+
+	c.ll.MoveToFront(el)
+	return entry.server, entry.body, true
+}
+
+func (c *queryCache) set(key, server, body string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, found := c.items[key]; found {
+		entry := el.Value.(*cacheEntry)
+		entry.server, entry.body, entry.fetchedAt = server, body, time.Now()
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&cacheEntry{key: key, server: server, body: body, fetchedAt: time.Now()})
+	c.items[key] = el
+
+	if c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*cacheEntry).key)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package whois
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueryCacheGetSet(t *testing.T) {
+	c := newQueryCache(2, time.Hour)
+
+	if _, _, ok := c.get("a.com"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	c.set("a.com", "whois.a.example", "body-a")
+	server, body, ok := c.get("a.com")
+	if !ok || server != "whois.a.example" || body != "body-a" {
+		t.Fatalf("unexpected get result: %q %q %v", server, body, ok)
+	}
+}
+
+func TestQueryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newQueryCache(2, time.Hour)
+
+	c.set("a.com", "s", "a")
+	c.set("b.com", "s", "b")
+	// touch a.com so b.com becomes the least recently used entry
+	c.get("a.com")
+	c.set("c.com", "s", "c")
+
+	if _, _, ok := c.get("b.com"); ok {
+		t.Fatal("expected b.com to have been evicted")
+	}
+	if _, _, ok := c.get("a.com"); !ok {
+		t.Fatal("expected a.com to still be cached")
+	}
+	if _, _, ok := c.get("c.com"); !ok {
+		t.Fatal("expected c.com to be cached")
+	}
+}
+
+func TestQueryCacheTTLExpiry(t *testing.T) {
+	c := newQueryCache(10, time.Millisecond)
+	c.set("a.com", "s", "a")
+	time.Sleep(5 * time.Millisecond)
+
+	if _, _, ok := c.get("a.com"); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+}
+
+func TestQueryCacheSetOverwritesExisting(t *testing.T) {
+	c := newQueryCache(10, time.Hour)
+	c.set("a.com", "s1", "body-1")
+	c.set("a.com", "s2", "body-2")
+
+	server, body, ok := c.get("a.com")
+	if !ok || server != "s2" || body != "body-2" {
+		t.Fatalf("expected overwritten entry, got %q %q %v", server, body, ok)
+	}
+}
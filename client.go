@@ -0,0 +1,192 @@
+package whois
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/proxy"
+	"golang.org/x/time/rate"
+)
+
+// Client holds per-query configuration: timeouts, the dialer used to reach
+// whois servers, how many referral hops to follow, and optional rate
+// limiting and response caching
+// The zero value is a ready-to-use Client equivalent to calling WhoisContext:
+// a single non-chasing query against the domain's tld server
+type Client struct {
+	// TCPTimeout is the time waited for contacting the whois server
+	// Defaults to the package TCPTimeout if zero
+	TCPTimeout time.Duration
+
+	// ResponseTimeout is the time waited for the query to be served by the whois server
+	// Defaults to the package ResponseTimeout if zero
+	ResponseTimeout time.Duration
+
+	// Dialer is used to reach whois servers, defaults to net.Dialer if nil
+	Dialer proxy.ContextDialer
+
+	// ReferralDepth is the maximum number of referral hops Query will follow
+	// Zero (the default) means no chasing, a single query against the tld
+	// server. Set to -1 to use the package ReferralDepth default instead
+	ReferralDepth int
+
+	// DefaultRPS and DefaultBurst configure the rate limit applied to any
+	// server with no override set via SetLimit. A DefaultRPS of zero means
+	// unlimited; with a positive DefaultRPS, a DefaultBurst below 1 makes
+	// every rate-limited query fail
+	DefaultRPS   float64
+	DefaultBurst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	cache    *queryCache
+}
+
+func (c *Client) tcpTimeout() time.Duration {
+	if c.TCPTimeout > 0 {
+		return c.TCPTimeout
+	}
+	return TCPTimeout
+}
+
+func (c *Client) responseTimeout() time.Duration {
+	if c.ResponseTimeout > 0 {
+		return c.ResponseTimeout
+	}
+	return ResponseTimeout
+}
+
+func (c *Client) dialer() proxy.ContextDialer {
+	if c.Dialer != nil {
+		return c.Dialer
+	}
+	return &net.Dialer{}
+}
+
+func (c *Client) referralDepth() int {
+	if c.ReferralDepth < 0 {
+		return ReferralDepth
+	}
+	return c.ReferralDepth
+}
+
+// SetLimit sets the rate limit applied to server, in requests per second
+// with the given burst. Passing rps <= 0 removes any override, falling
+// back to DefaultRPS/DefaultBurst
+func (c *Client) SetLimit(server string, rps float64, burst int) {
+	server = strings.ToLower(server)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.limiters == nil {
+		c.limiters = make(map[string]*rate.Limiter)
+	}
+	if rps <= 0 {
+		delete(c.limiters, server)
+		return
+	}
+	c.limiters[server] = rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+// SetCache enables response caching, keyed on lowercased domain, holding at
+// most maxEntries responses for ttl before they're considered stale
+// Passing maxEntries <= 0 disables the cache. ttl <= 0 uses defaultCacheTTL
+func (c *Client) SetCache(maxEntries int, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if maxEntries <= 0 {
+		c.cache = nil
+		return
+	}
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	c.cache = newQueryCache(maxEntries, ttl)
+}
+
+// limiterFor returns the rate limiter for server, lazily creating one from
+// DefaultRPS/DefaultBurst the first time an unconfigured server is seen.
+// Returns nil when no limit applies
+func (c *Client) limiterFor(server string) *rate.Limiter {
+	server = strings.ToLower(server)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if l, ok := c.limiters[server]; ok {
+		return l
+	}
+	if c.DefaultRPS <= 0 {
+		return nil
+	}
+
+	if c.limiters == nil {
+		c.limiters = make(map[string]*rate.Limiter)
+	}
+	l := rate.NewLimiter(rate.Limit(c.DefaultRPS), c.DefaultBurst)
+	c.limiters[server] = l
+	return l
+}
+
+// rateLimitedDialer wraps the configured Dialer so a token is acquired from
+// the destination server's rate limiter, if any, before dialing
+func (c *Client) rateLimitedDialer() whoisDial {
+	base := c.dialer().DialContext
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if limiter := c.limiterFor(strings.TrimSuffix(address, Port)); limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return base(ctx, network, address)
+	}
+}
+
+func (c *Client) cacheGet(key string) (server, body string, ok bool) {
+	c.mu.Lock()
+	cache := c.cache
+	c.mu.Unlock()
+	if cache == nil {
+		return "", "", false
+	}
+	return cache.get(key)
+}
+
+func (c *Client) cacheSet(key, server, body string) {
+	c.mu.Lock()
+	cache := c.cache
+	c.mu.Unlock()
+	if cache == nil {
+		return
+	}
+	cache.set(key, server, body)
+}
+
+// Query looks up domain against its tld's whois server, following referrals
+// up to c.ReferralDepth hops, and returns the server and body of the last
+// response in the chain. A cached response is returned as-is if SetCache is
+// enabled and still fresh
+func (c *Client) Query(ctx context.Context, domain string) (string, string, error) {
+	key := strings.ToLower(domain)
+	if server, body, ok := c.cacheGet(key); ok {
+		return server, body, nil
+	}
+
+	chain, err := whoisDeep(ctx, domain, c.rateLimitedDialer(), c.referralDepth(), c.tcpTimeout(), c.responseTimeout())
+	if err != nil {
+		return "", "", err
+	}
+	if len(chain) == 0 {
+		return "", "", fmt.Errorf("No whois server for %s", domain)
+	}
+
+	last := chain[len(chain)-1]
+	c.cacheSet(key, last.Server, last.Body)
+	return last.Server, last.Body, nil
+}
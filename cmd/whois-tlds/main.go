@@ -0,0 +1,202 @@
+// Command whois-tlds regenerates tldserv.go from an up to date tld->server
+// list, so newly delegated TLDs (IDN ccTLDs especially) don't require
+// forking the module
+//
+// Two sources are supported:
+//
+//	-source=debian (default) fetches the Debian whois package's tld_serv_list,
+//	a maintained tab-separated file mirrored from the rfc1036/whois project
+//
+//	-source=iana scrapes the list of delegated TLDs from IANA's root zone
+//	database and performs a live whois query against whois.iana.org for each
+//	one, reading the authoritative server from its "refer:" line
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	debianTldServListURL = "https://raw.githubusercontent.com/rfc1036/whois/next/tld_serv_list"
+	ianaRootZoneURL      = "https://www.iana.org/domains/root/db"
+	ianaWhoisServer      = "whois.iana.org"
+	queryTimeout         = 15 * time.Second
+)
+
+var (
+	source = flag.String("source", "debian", `list to fetch: "debian" or "iana"`)
+	output = flag.String("o", "tldserv.go", "output file path")
+)
+
+func main() {
+	flag.Parse()
+
+	var (
+		entries map[string]string
+		err     error
+	)
+
+	switch *source {
+	case "debian":
+		entries, err = fetchDebianList()
+	case "iana":
+		entries, err = fetchIANAList()
+	default:
+		log.Fatalf("whois-tlds: unknown -source %q", *source)
+	}
+	if err != nil {
+		log.Fatalf("whois-tlds: %v", err)
+	}
+
+	if err := writeTldServ(*output, *source, entries); err != nil {
+		log.Fatalf("whois-tlds: %v", err)
+	}
+}
+
+// fetchDebianList downloads and parses the Debian whois package's
+// tab-separated "tld\twhois.server" list, skipping blank lines and comments
+func fetchDebianList() (map[string]string, error) {
+	resp, err := http.Get(debianTldServListURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		tld, server := strings.ToLower(fields[0]), fields[1]
+		if !strings.Contains(server, ".") {
+			continue // NONE/WEB markers and the like, not a real server
+		}
+		entries["."+strings.TrimPrefix(tld, ".")] = server
+	}
+	return entries, scanner.Err()
+}
+
+var tldLinkPattern = regexp.MustCompile(`/domains/root/db/([a-z0-9-]+|xn--[a-z0-9-]+)\.html`)
+
+// fetchIANAList scrapes the delegated TLD names from IANA's root zone
+// database and resolves each one's authoritative server with a live query
+func fetchIANAList() (map[string]string, error) {
+	resp, err := http.Get(ianaRootZoneURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	entries := make(map[string]string)
+	for _, m := range tldLinkPattern.FindAllStringSubmatch(string(body), -1) {
+		tld := m[1]
+		if seen[tld] {
+			continue
+		}
+		seen[tld] = true
+
+		server, err := referralServer(tld)
+		if err != nil {
+			log.Printf("whois-tlds: skipping .%s: %v", tld, err)
+			continue
+		}
+		entries["."+tld] = server
+	}
+	return entries, nil
+}
+
+// referralServer performs a raw whois query against whois.iana.org for tld
+// and returns the server named in its "refer:" line
+func referralServer(tld string) (string, error) {
+	conn, err := net.DialTimeout("tcp", ianaWhoisServer+":43", queryTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(queryTimeout))
+
+	fmt.Fprintf(conn, "%s\r\n", tld)
+	body, err := ioutil.ReadAll(conn)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(strings.ToLower(line), "refer:") {
+			return strings.TrimSpace(line[len("refer:"):]), nil
+		}
+	}
+	return "", fmt.Errorf("no refer: line in IANA response")
+}
+
+// tldServTemplate emits a map literal plus an init() that calls
+// RegisterTLDServer for every entry, the same wiring tldseed.go uses, so the
+// generated file actually reaches the live trie instead of sitting unused
+var tldServTemplate = template.Must(template.New("tldserv").Parse(`// Code generated by cmd/whois-tlds from {{.Source}}; DO NOT EDIT.
+
+package whois
+
+var tldGenerated = map[string]string{
+{{range .Entries}}	{{printf "%q" .TLD}}: {{printf "%q" .Server}},
+{{end}}}
+
+func init() {
+	for tld, server := range tldGenerated {
+		RegisterTLDServer(tld, server)
+	}
+}
+`))
+
+type tldServEntry struct {
+	TLD    string
+	Server string
+}
+
+func writeTldServ(path, source string, entries map[string]string) error {
+	tlds := make([]string, 0, len(entries))
+	for tld := range entries {
+		tlds = append(tlds, tld)
+	}
+	sort.Strings(tlds)
+
+	ordered := make([]tldServEntry, 0, len(tlds))
+	for _, tld := range tlds {
+		ordered = append(ordered, tldServEntry{TLD: tld, Server: entries[tld]})
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return tldServTemplate.Execute(f, struct {
+		Source  string
+		Entries []tldServEntry
+	}{Source: source, Entries: ordered})
+}
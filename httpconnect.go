@@ -0,0 +1,106 @@
+package whois
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/proxy"
+)
+
+// ProxiedHTTP queries the database of the domain's tld via an HTTP(S) CONNECT proxy
+// proxyAddr is the proxy's host:port (an http:// or https:// scheme is stripped if present)
+// auth can be nil if the proxy requires no authentication
+func ProxiedHTTP(domain, proxyAddr string, auth *proxy.Auth) (string, string, error) {
+	return ProxiedHTTPContext(context.Background(), domain, proxyAddr, auth)
+}
+
+// ProxiedHTTPContext is ProxiedHTTP with a caller-supplied context for cancellation and deadlines
+func ProxiedHTTPContext(ctx context.Context, domain, proxyAddr string, auth *proxy.Auth) (string, string, error) {
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialHTTPConnect(ctx, proxyAddr, address, auth)
+	}
+	return whois(ctx, domain, dial, TCPTimeout, ResponseTimeout)
+}
+
+// dialHTTPConnect opens a TCP connection to proxyAddr and tunnels to targetAddr
+// using the HTTP CONNECT method, returning the raw connection on success
+func dialHTTPConnect(ctx context.Context, proxyAddr, targetAddr string, auth *proxy.Auth) (net.Conn, error) {
+	host := proxyAddr
+	if u, err := url.Parse(proxyAddr); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	d := &net.Dialer{}
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", targetAddr, targetAddr)
+	if auth != nil {
+		creds := base64.StdEncoding.EncodeToString([]byte(auth.User + ":" + auth.Password))
+		req += "Proxy-Authorization: Basic " + creds + "\r\n"
+	}
+	req += "\r\n"
+
+	if _, err := conn.Write([]byte(req)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	status, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	parts := strings.SplitN(strings.TrimSpace(status), " ", 3)
+	if len(parts) < 2 {
+		conn.Close()
+		return nil, fmt.Errorf("whois: malformed CONNECT response: %q", status)
+	}
+	code, err := strconv.Atoi(parts[1])
+	if err != nil || code < 200 || code >= 300 {
+		conn.Close()
+		return nil, fmt.Errorf("whois: proxy CONNECT failed: %q", strings.TrimSpace(status))
+	}
+
+	// Drain the remaining response headers up to the blank line
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+	}
+
+	// r may have buffered tunnel bytes read past the header block; wrap conn
+	// so callers see those bytes before falling through to the raw socket
+	return &bufferedConn{Conn: conn, r: r}, nil
+}
+
+// bufferedConn is a net.Conn whose Read is served from a bufio.Reader first,
+// so that bytes already pulled into the reader's buffer aren't lost once the
+// caller switches to reading the connection directly
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (b *bufferedConn) Read(p []byte) (int, error) {
+	return b.r.Read(p)
+}
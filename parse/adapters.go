@@ -0,0 +1,191 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseNominet handles whois.nic.uk (.uk) responses, which group fields
+// under unindented section headers ("Relevant dates:", "Registrar:",
+// "Name servers:") with the actual values indented on the following lines
+func parseNominet(body string) (*Record, error) {
+	r := &Record{}
+	lines := strings.Split(body, "\n")
+
+	var section string
+	for _, raw := range lines {
+		line := strings.TrimRight(raw, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			if strings.HasPrefix(trimmed, "Domain name:") {
+				r.Domain = strings.TrimSpace(strings.TrimPrefix(trimmed, "Domain name:"))
+				section = ""
+				if r.Domain == "" {
+					section = "domain name"
+				}
+				continue
+			}
+			section = strings.ToLower(strings.TrimSuffix(trimmed, ":"))
+			continue
+		}
+
+		switch section {
+		case "domain name":
+			if r.Domain == "" {
+				r.Domain = trimmed
+			}
+		case "registrar":
+			if r.Registrar == "" {
+				r.Registrar = trimmed
+			}
+		case "registrant":
+			if r.Registrant.Name == "" {
+				r.Registrant.Name = trimmed
+			}
+		case "relevant dates":
+			idx := strings.Index(trimmed, ":")
+			if idx < 0 {
+				continue
+			}
+			key := strings.ToLower(strings.TrimSpace(trimmed[:idx]))
+			value := strings.TrimSpace(trimmed[idx+1:])
+			switch key {
+			case "registered on":
+				if t, ok := parseDate(value); ok {
+					r.CreationDate = t
+				}
+			case "expiry date":
+				if t, ok := parseDate(value); ok {
+					r.ExpiryDate = t
+				}
+			case "last updated":
+				if t, ok := parseDate(value); ok {
+					r.UpdatedDate = t
+				}
+			}
+		case "registration status":
+			r.Statuses = append(r.Statuses, trimmed)
+		case "name servers":
+			r.NameServers = append(r.NameServers, strings.ToLower(strings.Fields(trimmed)[0]))
+		}
+	}
+
+	if r.Domain == "" {
+		return nil, fmt.Errorf("whois/parse: no domain found in response")
+	}
+
+	return r, nil
+}
+
+// parseDenic handles whois.denic.de (.de) responses, which use lowercase
+// "Key: value" fields, group contact details under bracketed section
+// headers ("[Admin-c]", "[Tech-c]"), and, for privacy reasons, omit
+// creation/expiry dates and the domain holder's contact details
+func parseDenic(body string) (*Record, error) {
+	r := &Record{}
+	var section string
+
+	for _, raw := range strings.Split(body, "\n") {
+		line := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.Trim(line, "[]"))
+			continue
+		}
+
+		idx := strings.Index(line, ":")
+		if idx < 0 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[:idx]))
+		value := strings.TrimSpace(line[idx+1:])
+		if value == "" {
+			continue
+		}
+
+		switch section {
+		case "admin-c":
+			assignContactField(&r.Admin, key, value)
+			continue
+		case "tech-c":
+			assignContactField(&r.Tech, key, value)
+			continue
+		}
+
+		switch key {
+		case "domain":
+			r.Domain = value
+		case "nserver":
+			r.NameServers = append(r.NameServers, strings.ToLower(strings.Fields(value)[0]))
+		case "status":
+			r.Statuses = append(r.Statuses, value)
+		case "changed":
+			if t, ok := parseDate(value); ok {
+				r.UpdatedDate = t
+			}
+		}
+	}
+
+	if r.Domain == "" {
+		return nil, fmt.Errorf("whois/parse: no domain found in response")
+	}
+
+	return r, nil
+}
+
+// parseJPRS handles whois.jprs.jp (.jp) responses, which key fields with
+// bracketed labels ("[Domain Name]", "[Registered Date]") rather than colons
+func parseJPRS(body string) (*Record, error) {
+	r := &Record{}
+
+	for _, raw := range strings.Split(body, "\n") {
+		line := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		end := strings.Index(line, "]")
+		if end < 0 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[1:end]))
+		value := strings.TrimSpace(line[end+1:])
+		if value == "" {
+			continue
+		}
+
+		switch key {
+		case "domain name":
+			r.Domain = strings.ToLower(value)
+		case "registrant":
+			r.Registrant.Organization = value
+		case "name server":
+			r.NameServers = append(r.NameServers, strings.ToLower(value))
+		case "status":
+			r.Statuses = append(r.Statuses, value)
+		case "registered date":
+			if t, ok := parseDate(value); ok {
+				r.CreationDate = t
+			}
+		case "last update":
+			if t, ok := parseDate(value); ok {
+				r.UpdatedDate = t
+			}
+		}
+	}
+
+	if r.Domain == "" {
+		return nil, fmt.Errorf("whois/parse: no domain found in response")
+	}
+
+	return r, nil
+}
@@ -0,0 +1,188 @@
+// Package parse normalizes the heterogeneous raw text returned by whois
+// servers into a common Record structure
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Contact holds the address fields commonly present in a whois contact block
+type Contact struct {
+	Name         string
+	Organization string
+	Email        string
+	Phone        string
+	Address      string
+	City         string
+	State        string
+	PostalCode   string
+	Country      string
+}
+
+// Record is a normalized view over a raw whois response
+type Record struct {
+	Domain               string
+	Registrar            string
+	RegistrarWhoisServer string
+	CreationDate         time.Time
+	UpdatedDate          time.Time
+	ExpiryDate           time.Time
+	Statuses             []string
+	NameServers          []string
+	DNSSEC               bool
+	Registrant           Contact
+	Admin                Contact
+	Tech                 Contact
+}
+
+// adapters maps a whois server (as returned by queryServer, lowercased) to
+// the parser best suited to its response format
+var adapters = map[string]func(string) (*Record, error){
+	"whois.verisign-grs.com": parseGeneric,
+	"whois.nic.uk":           parseNominet,
+	"whois.denic.de":         parseDenic,
+	"whois.jprs.jp":          parseJPRS,
+}
+
+// Parse normalizes body, the raw response obtained from server, into a Record
+// Servers with no dedicated adapter fall back to a generic key:value parser
+func Parse(server, body string) (*Record, error) {
+	if adapter, ok := adapters[strings.ToLower(strings.TrimSpace(server))]; ok {
+		return adapter(body)
+	}
+	return parseGeneric(body)
+}
+
+// dateLayouts are tried in order until one parses a date value
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02-Jan-2006",
+	"02.01.2006",
+	"2006.01.02",
+	"2006/01/02 15:04:05 (MST)",
+	"2006/01/02",
+	"20060102",
+}
+
+func parseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseGeneric handles the common "Key: value" convention shared by most
+// gTLD registries (Verisign included) and is the fallback for unknown servers
+func parseGeneric(body string) (*Record, error) {
+	r := &Record{}
+
+	for _, raw := range strings.Split(body, "\n") {
+		line := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		if line == "" || strings.HasPrefix(line, "%") || strings.HasPrefix(line, ">>>") {
+			continue
+		}
+
+		idx := strings.Index(line, ":")
+		if idx < 0 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[:idx]))
+		value := strings.TrimSpace(line[idx+1:])
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(key, "registrant "):
+			assignContactField(&r.Registrant, strings.TrimPrefix(key, "registrant "), value)
+			continue
+		case strings.HasPrefix(key, "admin "):
+			assignContactField(&r.Admin, strings.TrimPrefix(key, "admin "), value)
+			continue
+		case strings.HasPrefix(key, "tech "):
+			assignContactField(&r.Tech, strings.TrimPrefix(key, "tech "), value)
+			continue
+		}
+
+		switch key {
+		case "domain name":
+			r.Domain = value
+		case "registrar":
+			r.Registrar = value
+		case "registrar whois server":
+			r.RegistrarWhoisServer = value
+		case "creation date", "created", "created on", "registered on":
+			if t, ok := parseDate(value); ok {
+				r.CreationDate = t
+			}
+		case "updated date", "last updated", "modified":
+			if t, ok := parseDate(value); ok {
+				r.UpdatedDate = t
+			}
+		case "registry expiry date", "expiry date", "expiration date", "registrar registration expiration date":
+			if t, ok := parseDate(value); ok {
+				r.ExpiryDate = t
+			}
+		case "domain status":
+			r.Statuses = append(r.Statuses, value)
+		case "name server", "nserver":
+			r.NameServers = append(r.NameServers, strings.ToLower(value))
+		case "dnssec":
+			r.DNSSEC = isSignedValue(value)
+		}
+	}
+
+	if r.Domain == "" {
+		return nil, fmt.Errorf("whois/parse: no domain found in response")
+	}
+
+	return r, nil
+}
+
+// assignContactField sets the field of c named by the part of a whois key
+// that follows a "Registrant "/"Admin "/"Tech " prefix (e.g. "name", "email")
+func assignContactField(c *Contact, field, value string) {
+	switch field {
+	case "name":
+		c.Name = value
+	case "organization", "org":
+		c.Organization = value
+	case "email":
+		c.Email = value
+	case "phone", "phone number":
+		c.Phone = value
+	case "street", "address", "street address":
+		if c.Address == "" {
+			c.Address = value
+		} else {
+			c.Address += ", " + value
+		}
+	case "city":
+		c.City = value
+	case "state/province", "state", "province":
+		c.State = value
+	case "postal code", "zip", "zip code":
+		c.PostalCode = value
+	case "country":
+		c.Country = value
+	}
+}
+
+func isSignedValue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "signed", "signeddelegation", "yes", "true":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,28 @@
+package whois
+
+import (
+	"context"
+
+	"github.com/FlyinDoji/whois/parse"
+)
+
+// WhoisParsed queries the database of the domain's tld and additionally
+// parses the response into a normalized parse.Record
+func WhoisParsed(domain string) (*parse.Record, string, error) {
+	return WhoisParsedContext(context.Background(), domain)
+}
+
+// WhoisParsedContext is WhoisParsed with a caller-supplied context for cancellation and deadlines
+func WhoisParsedContext(ctx context.Context, domain string) (*parse.Record, string, error) {
+	server, body, err := WhoisContext(ctx, domain)
+	if err != nil {
+		return nil, "", err
+	}
+
+	record, err := parse.Parse(server, body)
+	if err != nil {
+		return nil, body, err
+	}
+
+	return record, body, nil
+}
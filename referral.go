@@ -0,0 +1,142 @@
+package whois
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ianaServer is the authoritative bootstrap server for TLDs with no
+// hard-coded entry in tldServers
+const ianaServer = "whois.iana.org"
+
+// ReferralDepth is the maximum number of referral hops WhoisDeep will follow
+// before giving up
+var ReferralDepth = 3
+
+// Response holds the whois server that was queried and the raw body it returned
+type Response struct {
+	Server string
+	Body   string
+}
+
+// WhoisDeep queries the domain's tld server and follows any thin-registry
+// referral (a "Registrar WHOIS Server:" or "ReferralServer:" line) to the
+// authoritative registrar's whois server, up to ReferralDepth hops
+// Domains whose tld has no hard-coded entry fall back to a live lookup
+// against the IANA bootstrap server
+func WhoisDeep(domain string) ([]Response, error) {
+	d := &net.Dialer{}
+	return whoisDeep(context.Background(), domain, d.DialContext, ReferralDepth, TCPTimeout, ResponseTimeout)
+}
+
+func whoisDeep(ctx context.Context, domain string, dial whoisDial, maxHops int, tcpTimeout, responseTimeout time.Duration) ([]Response, error) {
+	tld, ok := extractTLD(domain)
+	if !ok {
+		var err error
+		tld, err = extractTLDLive(ctx, domain, dial, tcpTimeout, responseTimeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dial = withTCPTimeout(dial, tcpTimeout)
+
+	var chain []Response
+	visited := make(map[string]bool)
+	server := tld.server
+
+	for hop := 0; hop <= maxHops; hop++ {
+		if visited[server] {
+			break
+		}
+		visited[server] = true
+
+		_, body, err := queryServer(ctx, domain, server, dial, responseTimeout)
+		if err != nil {
+			return chain, err
+		}
+		chain = append(chain, Response{Server: server, Body: body})
+
+		next, ok := extractReferral(body)
+		if !ok || next == server {
+			break
+		}
+		server = next
+	}
+
+	return chain, nil
+}
+
+// extractTLDLive looks up domain's tld against the IANA bootstrap server,
+// seeding a tldServ from the "refer:" line of its response
+func extractTLDLive(ctx context.Context, domain string, dial whoisDial, tcpTimeout, responseTimeout time.Duration) (tldServ, error) {
+	tld := domain
+	if idx := strings.LastIndex(domain, "."); idx >= 0 {
+		tld = domain[idx+1:]
+	}
+
+	_, body, err := queryServer(ctx, tld, ianaServer, withTCPTimeout(dial, tcpTimeout), responseTimeout)
+	if err != nil {
+		return tldServ{}, err
+	}
+
+	server, ok := extractReferral(body)
+	if !ok {
+		return tldServ{}, fmt.Errorf("whois: no referral found in IANA response for .%s", tld)
+	}
+
+	return tldServ{tld: "." + tld, server: server}, nil
+}
+
+// referralFields are the key:value fields that point at another whois server,
+// matched case-insensitively
+var referralFields = map[string]bool{
+	"registrar whois server": true,
+	"referralserver":         true,
+	"whois server":           true,
+	"refer":                  true,
+}
+
+// extractReferral scans body for a referral field and returns the hostname
+// it points to
+func extractReferral(body string) (string, bool) {
+	for _, raw := range strings.Split(body, "\n") {
+		line := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		idx := strings.Index(line, ":")
+		if idx < 0 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[:idx]))
+		if !referralFields[key] {
+			continue
+		}
+
+		value := strings.TrimSpace(line[idx+1:])
+		value = strings.TrimPrefix(strings.ToLower(value), "whois://")
+		value = strings.TrimSuffix(value, "/")
+		if host, ok := validHostname(value); ok {
+			return host, true
+		}
+	}
+	return "", false
+}
+
+// validHostname reports whether s looks like a bare hostname (no port,
+// scheme, or path) suitable for use as a whois server address
+func validHostname(s string) (string, bool) {
+	if s == "" {
+		return "", false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-':
+		default:
+			return "", false
+		}
+	}
+	return s, true
+}
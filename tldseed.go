@@ -0,0 +1,47 @@
+package whois
+
+// tldSeed is a small built-in table of whois servers for the TLDs most
+// commonly looked up, registered at package init via RegisterTLDServer so
+// the package is usable out of the box with no generation step
+// Anything missing here (new gTLDs, IDN ccTLDs, ...) can be added at
+// runtime with RegisterTLDServer, or by running cmd/whois-tlds and
+// registering its output the same way
+var tldSeed = map[string]string{
+	".com":   "whois.verisign-grs.com",
+	".net":   "whois.verisign-grs.com",
+	".org":   "whois.pir.org",
+	".info":  "whois.afilias.net",
+	".biz":   "whois.biz",
+	".io":    "whois.nic.io",
+	".co":    "whois.nic.co",
+	".me":    "whois.nic.me",
+	".dev":   "whois.nic.google",
+	".app":   "whois.nic.google",
+	".us":    "whois.nic.us",
+	".uk":    "whois.nic.uk",
+	".co.uk": "whois.nic.uk",
+	".de":    "whois.denic.de",
+	".fr":    "whois.nic.fr",
+	".nl":    "whois.domain-registry.nl",
+	".eu":    "whois.eu",
+	".es":    "whois.nic.es",
+	".it":    "whois.nic.it",
+	".ch":    "whois.nic.ch",
+	".se":    "whois.iis.se",
+	".ru":    "whois.tcinet.ru",
+	".jp":    "whois.jprs.jp",
+	".cn":    "whois.cnnic.cn",
+	".in":    "whois.registry.in",
+	".au":    "whois.auda.org.au",
+	".ca":    "whois.cira.ca",
+	".br":    "whois.registro.br",
+	".mx":    "whois.mx",
+	".nz":    "whois.srs.net.nz",
+	".ie":    "whois.iedr.ie",
+}
+
+func init() {
+	for tld, server := range tldSeed {
+		RegisterTLDServer(tld, server)
+	}
+}
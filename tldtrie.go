@@ -0,0 +1,109 @@
+package whois
+
+import (
+	"strings"
+	"sync"
+)
+
+// tldServ pairs a tld (with a leading dot, e.g. ".co.uk") with the whois
+// server authoritative for it
+type tldServ struct {
+	tld    string
+	server string
+}
+
+// tldNode is a node in the reverse-label tld trie: each edge is one DNS
+// label, walked from the rightmost label of a domain inward, so ".co.uk"
+// is stored as root -> "uk" -> "co"
+type tldNode struct {
+	children map[string]*tldNode
+	server   string
+	isLeaf   bool
+}
+
+var (
+	tldMu   sync.RWMutex
+	tldRoot = &tldNode{children: make(map[string]*tldNode)}
+)
+
+// RegisterTLDServer registers or overrides the whois server used for tld
+// (e.g. "com" or "co.uk", with or without a leading dot)
+func RegisterTLDServer(tld, server string) {
+	labels := tldLabels(tld)
+
+	tldMu.Lock()
+	defer tldMu.Unlock()
+
+	node := tldRoot
+	for i := len(labels) - 1; i >= 0; i-- {
+		child, ok := node.children[labels[i]]
+		if !ok {
+			child = &tldNode{children: make(map[string]*tldNode)}
+			node.children[labels[i]] = child
+		}
+		node = child
+	}
+	node.isLeaf = true
+	node.server = server
+}
+
+// UnregisterTLDServer removes any whois server registered for tld
+func UnregisterTLDServer(tld string) {
+	labels := tldLabels(tld)
+
+	tldMu.Lock()
+	defer tldMu.Unlock()
+
+	node := tldRoot
+	for i := len(labels) - 1; i >= 0; i-- {
+		child, ok := node.children[labels[i]]
+		if !ok {
+			return
+		}
+		node = child
+	}
+	node.isLeaf = false
+	node.server = ""
+}
+
+func tldLabels(tld string) []string {
+	tld = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tld), "."))
+	return strings.Split(tld, ".")
+}
+
+// extractTLD finds the whois server registered for the longest tld suffix
+// of domain, walking the trie one label at a time from the right so that a
+// multi-label tld (".co.uk") wins over a shorter one (".uk")
+func extractTLD(domain string) (tldServ, bool) {
+	labels := strings.Split(strings.ToLower(domain), ".")
+
+	tldMu.RLock()
+	defer tldMu.RUnlock()
+
+	node := tldRoot
+	var (
+		found     bool
+		server    string
+		matchedAt int
+	)
+
+	// i > 0 keeps at least one label unconsumed as the domain name itself
+	for i := len(labels) - 1; i > 0; i-- {
+		child, ok := node.children[labels[i]]
+		if !ok {
+			break
+		}
+		node = child
+		if node.isLeaf {
+			found = true
+			server = node.server
+			matchedAt = i
+		}
+	}
+
+	if !found {
+		return tldServ{}, false
+	}
+
+	return tldServ{tld: "." + strings.Join(labels[matchedAt:], "."), server: server}, true
+}
@@ -0,0 +1,66 @@
+package whois
+
+import "testing"
+
+func TestExtractTLDLongestSuffixMatch(t *testing.T) {
+	RegisterTLDServer(".test", "whois.test.example")
+	RegisterTLDServer(".co.test", "whois.co.test.example")
+	defer UnregisterTLDServer(".test")
+	defer UnregisterTLDServer(".co.test")
+
+	tld, ok := extractTLD("example.co.test")
+	if !ok {
+		t.Fatal("expected a match for example.co.test")
+	}
+	if tld.tld != ".co.test" || tld.server != "whois.co.test.example" {
+		t.Fatalf("expected the longer .co.test suffix to win, got %+v", tld)
+	}
+
+	tld, ok = extractTLD("example.test")
+	if !ok {
+		t.Fatal("expected a match for example.test")
+	}
+	if tld.tld != ".test" || tld.server != "whois.test.example" {
+		t.Fatalf("expected .test, got %+v", tld)
+	}
+}
+
+func TestExtractTLDUnregistered(t *testing.T) {
+	if _, ok := extractTLD("example.nosuchtld-zzz"); ok {
+		t.Fatal("expected no match for an unregistered tld")
+	}
+}
+
+func TestExtractTLDRequiresALeftoverLabel(t *testing.T) {
+	RegisterTLDServer(".bareleaftest", "whois.bare.example")
+	defer UnregisterTLDServer(".bareleaftest")
+
+	if _, ok := extractTLD("bareleaftest"); ok {
+		t.Fatal("a domain identical to a registered tld should not match itself")
+	}
+}
+
+func TestUnregisterTLDServer(t *testing.T) {
+	RegisterTLDServer(".unregtest", "whois.unreg.example")
+
+	if _, ok := extractTLD("example.unregtest"); !ok {
+		t.Fatal("expected a match before unregistering")
+	}
+
+	UnregisterTLDServer(".unregtest")
+
+	if _, ok := extractTLD("example.unregtest"); ok {
+		t.Fatal("expected no match after unregistering")
+	}
+}
+
+func TestRegisterTLDServerOverridesExisting(t *testing.T) {
+	RegisterTLDServer(".overridetest", "whois.one.example")
+	RegisterTLDServer(".overridetest", "whois.two.example")
+	defer UnregisterTLDServer(".overridetest")
+
+	tld, ok := extractTLD("example.overridetest")
+	if !ok || tld.server != "whois.two.example" {
+		t.Fatalf("expected the second registration to win, got %+v ok=%v", tld, ok)
+	}
+}
@@ -4,9 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net"
-	"strings"
 	"time"
 
 	"golang.org/x/net/proxy"
@@ -20,47 +18,49 @@ const (
 	TCPTimeout      = time.Duration(12) * time.Second
 )
 
-type tldServ struct {
-	tld    string
-	server string
-}
-
 type whoisDial func(ctx context.Context, network string, address string) (net.Conn, error)
 
-var tldServers []tldServ
-
-func extractTLD(domain string) (tldServ, bool) {
-
-	for _, s := range tldServers {
-		if len(s.tld) > len(domain) {
-			continue
-		}
-		p := len(domain) - len(s.tld)
-		if domain[p] != []byte(".")[0] {
-			continue
-		}
-		if domain[p:] == s.tld {
-			return s, true
-		}
+// withTCPTimeout wraps dial so the connect phase is bounded by timeout on
+// top of whatever deadline/cancellation the caller's ctx already carries
+func withTCPTimeout(dial whoisDial, timeout time.Duration) whoisDial {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return dial(dialCtx, network, address)
 	}
+}
 
-	return tldServ{}, false
+// readAll reads conn to completion, aborting early by forcing the read
+// deadline if ctx is done before the read finishes on its own
+func readAll(ctx context.Context, conn net.Conn) ([]byte, error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
+	return ioutil.ReadAll(conn)
 }
 
-func queryServer(domain, server string, dial whoisDial) (string, string, error) {
+func queryServer(ctx context.Context, domain, server string, dial whoisDial, responseTimeout time.Duration) (string, string, error) {
 
-	ctx, cancel := context.WithTimeout(context.Background(), TCPTimeout)
-	defer cancel()
 	conn, err := dial(ctx, "tcp", server+Port)
 	if err != nil {
 		return "", "", err
 	}
+	defer conn.Close()
 
-	_ = conn.SetDeadline(time.Now().Add(ResponseTimeout))
+	deadline := time.Now().Add(responseTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
 
-	defer conn.Close()
 	fmt.Fprintf(conn, "%s\r\n", domain)
-	b, err := ioutil.ReadAll(conn)
+	b, err := readAll(ctx, conn)
 	if err != nil {
 		return "", "", err
 	}
@@ -68,60 +68,66 @@ func queryServer(domain, server string, dial whoisDial) (string, string, error)
 	return server, string(b), nil
 }
 
-func whois(domain string, dial whoisDial) (string, string, error) {
+// whois resolves domain's tld server, falling back to a live IANA bootstrap
+// lookup (see extractTLDLive) when there's no hard-coded entry, then queries it
+func whois(ctx context.Context, domain string, dial whoisDial, tcpTimeout, responseTimeout time.Duration) (string, string, error) {
 
-	if tld, ok := extractTLD(domain); ok {
-		return queryServer(domain, tld.server, dial)
+	tld, ok := extractTLD(domain)
+	if !ok {
+		var err error
+		tld, err = extractTLDLive(ctx, domain, dial, tcpTimeout, responseTimeout)
+		if err != nil {
+			return "", "", fmt.Errorf("No whois server for %s: %w", domain, err)
+		}
 	}
-	return "", "", fmt.Errorf("No whois server for %s", domain)
+	return queryServer(ctx, domain, tld.server, withTCPTimeout(dial, tcpTimeout), responseTimeout)
 }
 
 // Whois queries the database of the domain's tld
 // Use the default net.Dial function to contact the whois server
 func Whois(domain string) (string, string, error) {
+	return WhoisContext(context.Background(), domain)
+}
+
+// WhoisContext is Whois with a caller-supplied context for cancellation and deadlines
+func WhoisContext(ctx context.Context, domain string) (string, string, error) {
 	d := &net.Dialer{}
-	return whois(domain, d.DialContext)
+	return whois(ctx, domain, d.DialContext, TCPTimeout, ResponseTimeout)
 }
 
 // Proxied queries the database of the domain's tld via SOCKS5 proxy
 // Uses the proxy.Dialer.Dial function to contact the whois server
 // p can be nil if no authentication is required
 func Proxied(domain, proxyAddr string, p *proxy.Auth) (string, string, error) {
+	return ProxiedContext(context.Background(), domain, proxyAddr, p)
+}
+
+// ProxiedContext is Proxied with a caller-supplied context for cancellation and deadlines
+func ProxiedContext(ctx context.Context, domain, proxyAddr string, p *proxy.Auth) (string, string, error) {
 
 	dialer, err := proxy.SOCKS5("tcp", proxyAddr, p, proxy.Direct)
+	if err != nil {
+		return "", "", err
+	}
 
 	dc := dialer.(interface {
 		DialContext(ctx context.Context, network, addr string) (net.Conn, error)
 	})
 
-	if err != nil {
-		return "", "", err
-	}
-	return whois(domain, dc.DialContext)
+	return whois(ctx, domain, dc.DialContext, TCPTimeout, ResponseTimeout)
 }
 
 // OwnDialer supply your own dial function
 func OwnDialer(domain string, dialFun whoisDial) (string, string, error) {
-	return whois(domain, dialFun)
+	return OwnDialerContext(context.Background(), domain, dialFun)
+}
+
+// OwnDialerContext is OwnDialer with a caller-supplied context for cancellation and deadlines
+func OwnDialerContext(ctx context.Context, domain string, dialFun whoisDial) (string, string, error) {
+	return whois(ctx, domain, dialFun, TCPTimeout, ResponseTimeout)
 }
 
 // ProxyAuth authentication object for ProxiedWhois
 func ProxyAuth(user, passwd string) *proxy.Auth {
 	return &proxy.Auth{User: user, Password: passwd}
 }
-
-// Load tld servers
-func init() {
-	for i, l := range strings.Split(tldServerList, "\n") {
-		if l == "" {
-			continue
-		}
-		kv := strings.Split(l, "\t")
-		if len(kv) != 2 {
-			log.Fatalf("whois:tldserv.go:tldServerList incorrect format %q at line %d", kv, i+1)
-			continue
-		}
-
-		tldServers = append(tldServers, tldServ{tld: kv[0], server: kv[1]})
-	}
-}